@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/printer"
+	"go/token"
+	"strings"
+)
+
+// deprecated scans raw, a doc comment as returned by ast.CommentGroup.Text
+// or go/doc.Package.Doc, for a paragraph beginning with "Deprecated:" -
+// the convention documented in go/doc. Per that convention the marker
+// starts a new paragraph and runs until the next blank line; its lines
+// are joined with spaces. ok reports whether a marker was found.
+func deprecated(raw string) (message string, ok bool) {
+	for _, p := range strings.Split(raw, "\n\n") {
+		p = strings.TrimSpace(p)
+		if !strings.HasPrefix(p, "Deprecated:") {
+			continue
+		}
+		lines := strings.Split(p, "\n")
+		for i, l := range lines {
+			lines[i] = strings.TrimSpace(l)
+		}
+		return strings.Join(lines, " "), true
+	}
+	return "", false
+}
+
+// DeprecatedExports scans the package in dir and returns a Doc for every
+// exported declaration, or the package itself, whose doc comment carries
+// a Deprecated: marker. This backs the "-deprecated-only" CLI mode.
+func DeprecatedExports(fset *token.FileSet, dir string) ([]*Doc, error) {
+	files, err := parseDir(fset, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg, err := doc.NewFromFiles(fset, files, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*Doc
+	add := func(name string, decl ast.Node, rawDoc string) {
+		msg, ok := deprecated(rawDoc)
+		if !ok {
+			return
+		}
+		out = append(out, &Doc{
+			Name:         name,
+			Title:        declText(fset, decl),
+			Deprecated:   msg,
+			IsDeprecated: true,
+		})
+	}
+
+	if msg, ok := deprecated(pkg.Doc); ok {
+		out = append(out, &Doc{
+			Name:         pkg.Name,
+			Title:        fmt.Sprintf("package %s", pkg.Name),
+			Deprecated:   msg,
+			IsDeprecated: true,
+		})
+	}
+	for _, fn := range pkg.Funcs {
+		add(fn.Name, fn.Decl, fn.Doc)
+	}
+	for _, v := range pkg.Consts {
+		add(strings.Join(v.Names, ","), v.Decl, v.Doc)
+	}
+	for _, v := range pkg.Vars {
+		add(strings.Join(v.Names, ","), v.Decl, v.Doc)
+	}
+	for _, t := range pkg.Types {
+		add(t.Name, t.Decl, t.Doc)
+		for _, fn := range t.Methods {
+			add(t.Name+"."+fn.Name, fn.Decl, fn.Doc)
+		}
+		// go/doc reassigns constructors, and consts/vars of the type,
+		// from pkg.Funcs/Consts/Vars onto the Type itself.
+		for _, fn := range t.Funcs {
+			add(fn.Name, fn.Decl, fn.Doc)
+		}
+		for _, v := range t.Consts {
+			add(strings.Join(v.Names, ","), v.Decl, v.Doc)
+		}
+		for _, v := range t.Vars {
+			add(strings.Join(v.Names, ","), v.Decl, v.Doc)
+		}
+	}
+	return out, nil
+}
+
+// declText prints decl (a *ast.FuncDecl or *ast.GenDecl) without its doc
+// comment or, for a func, its body.
+func declText(fset *token.FileSet, decl ast.Node) string {
+	var nc ast.Node
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		cp := *d
+		cp.Doc, cp.Body = nil, nil
+		nc = &cp
+	case *ast.GenDecl:
+		cp := *d
+		cp.Doc = nil
+		nc = &cp
+	default:
+		return ""
+	}
+
+	var buf bytes.Buffer
+	cfg := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	if err := cfg.Fprint(&buf, fset, nc); err != nil {
+		return ""
+	}
+	return buf.String()
+}
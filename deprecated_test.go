@@ -0,0 +1,59 @@
+package main
+
+import (
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const deprecatedSrc = `package p
+
+// Widget does widget things.
+type Widget struct{}
+
+// OldWidget creates a Widget.
+//
+// Deprecated: use NewWidget instead.
+func OldWidget() *Widget { return &Widget{} }
+
+// Level is a widget level.
+type Level int
+
+// LevelOld is a level.
+//
+// Deprecated: use LevelNew instead.
+const LevelOld Level = 0
+
+// OldLevel is a level.
+//
+// Deprecated: use NewLevel instead.
+var OldLevel Level
+`
+
+func TestDeprecatedExports(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(deprecatedSrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	docs, err := DeprecatedExports(token.NewFileSet(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]bool{"OldWidget": false, "LevelOld": false, "OldLevel": false}
+	for _, d := range docs {
+		if _, ok := want[d.Name]; ok {
+			want[d.Name] = true
+		}
+		if !d.IsDeprecated || d.Deprecated == "" {
+			t.Errorf("%s: IsDeprecated/Deprecated not populated", d.Name)
+		}
+	}
+	for name, found := range want {
+		if !found {
+			t.Errorf("DeprecatedExports did not report %s as deprecated", name)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+
+	"golang.org/x/tools/go/loader"
+)
+
+// parseDir parses every .go file in dir, including _test.go files
+// (internal or external test package alike), into an *ast.File. It
+// returns an error for the first file that fails to parse, rather than
+// silently dropping it: a directory that doesn't fully parse shouldn't
+// look no different from one with nothing deprecated, no notes, and no
+// examples.
+func parseDir(fset *token.FileSet, dir string) ([]*ast.File, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*ast.File
+	for _, m := range matches {
+		f, err := parser.ParseFile(fset, m, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files found in %s", dir)
+	}
+	return files, nil
+}
+
+// Doc holds the documentation gathered for a single identifier, or for a
+// whole package when no particular identifier was requested.
+type Doc struct {
+	Import string `json:"import"`
+	Name   string `json:"name"`
+	Title  string `json:"decl"`
+	Doc    string `json:"doc"`
+
+	// Markdown holds the doc comment rendered as Markdown. It's only
+	// populated when the requested TextFormat.Mode is "markdown".
+	Markdown string `json:"markdown,omitempty"`
+
+	// Examples holds the runnable examples, from _test.go files, that
+	// document this identifier (or the package, when Name is a package).
+	Examples []Example `json:"examples,omitempty"`
+
+	// Methods, Funcs, Consts and Vars are populated when Doc describes a
+	// type: Methods is its method set (including promoted methods),
+	// Funcs are package-level constructors returning it, and Consts/Vars
+	// are package-level values declared with it.
+	Methods []*Doc `json:"methods,omitempty"`
+	Funcs   []*Doc `json:"funcs,omitempty"`
+	Consts  []*Doc `json:"consts,omitempty"`
+	Vars    []*Doc `json:"vars,omitempty"`
+
+	// Notes holds the package's BUG/TODO/FIXME markers, keyed by marker.
+	// Only populated for package-level Docs.
+	Notes map[string][]Note `json:"notes,omitempty"`
+
+	// Deprecated holds the message from a "Deprecated:" marker in the
+	// doc comment, if any. IsDeprecated reports whether one was found,
+	// since Deprecated is also empty for a non-deprecated symbol.
+	Deprecated   string `json:"deprecated,omitempty"`
+	IsDeprecated bool   `json:"isDeprecated,omitempty"`
+}
+
+// Example is a single runnable example, as produced by go/doc.Examples.
+type Example struct {
+	Name        string `json:"name"`
+	Doc         string `json:"doc,omitempty"`
+	Code        string `json:"code"`
+	Output      string `json:"output,omitempty"`
+	Unordered   bool   `json:"unordered,omitempty"`
+	EmptyOutput bool   `json:"emptyOutput,omitempty"`
+}
+
+// PackageDoc returns documentation for the package at importPath.
+func PackageDoc(fset *token.FileSet, importPath string, format *TextFormat) (*Doc, error) {
+	var conf loader.Config
+	conf.Fset = fset
+	conf.ParserMode = parser.ParseComments
+	conf.Import(importPath)
+
+	prog, err := conf.Load()
+	if err != nil {
+		return nil, err
+	}
+	pkgInfo := prog.Package(importPath)
+	if pkgInfo == nil {
+		return nil, fmt.Errorf("no package found for import path %q", importPath)
+	}
+
+	pkg, err := doc.NewFromFiles(fset, pkgInfo.Files, importPath)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Doc{
+		Import: importPath,
+		Name:   pkg.Name,
+		Title:  fmt.Sprintf("package %s", pkg.Name),
+	}
+	d.setText(pkg.Doc, format)
+	d.Notes = notesFromPackage(fset, pkg)
+	if len(pkgInfo.Files) > 0 {
+		dir := filepath.Dir(fset.Position(pkgInfo.Files[0].Pos()).Filename)
+		if exPkg, err := examplePackage(fset, importPath, dir); err == nil {
+			d.Examples = renderExamples(fset, exPkg.Examples)
+		}
+	}
+	return d, nil
+}
+
+// examplePackage parses every .go file in dir, including _test.go files
+// (internal or external test package alike), and builds the resulting
+// *doc.Package via go/doc.NewFromFiles. Passing the test files alongside
+// the rest of the package is what makes go/doc classify each runnable
+// example against the Func, Type, method or Package it documents -
+// following the ExampleFoo, ExampleFoo_Bar, ExampleType_Method and
+// ExampleType_Method_suffix conventions - instead of us reimplementing
+// that matching by hand.
+func examplePackage(fset *token.FileSet, importPath, dir string) (*doc.Package, error) {
+	files, err := parseDir(fset, dir)
+	if err != nil {
+		return nil, err
+	}
+	return doc.NewFromFiles(fset, files, importPath)
+}
+
+// examplesForObj returns the examples pkg associates with obj: a
+// function's own examples, a type's examples, or - for a method - the
+// examples attached to that method on its receiver type.
+func examplesForObj(pkg *doc.Package, obj types.Object) []*doc.Example {
+	switch o := obj.(type) {
+	case *types.Func:
+		sig := o.Type().(*types.Signature)
+		if sig.Recv() == nil {
+			for _, fn := range pkg.Funcs {
+				if fn.Name == o.Name() {
+					return fn.Examples
+				}
+			}
+			// go/doc files constructors under their result type.
+			for _, t := range pkg.Types {
+				for _, fn := range t.Funcs {
+					if fn.Name == o.Name() {
+						return fn.Examples
+					}
+				}
+			}
+			return nil
+		}
+		recv := receiverTypeName(sig.Recv().Type())
+		for _, t := range pkg.Types {
+			if t.Name != recv {
+				continue
+			}
+			for _, m := range t.Methods {
+				if m.Name == o.Name() {
+					return m.Examples
+				}
+			}
+		}
+	case *types.TypeName:
+		for _, t := range pkg.Types {
+			if t.Name == o.Name() {
+				return t.Examples
+			}
+		}
+	}
+	return nil
+}
+
+// receiverTypeName returns the name of t, or of the type t points to.
+func receiverTypeName(t types.Type) string {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	if n, ok := t.(*types.Named); ok {
+		return n.Obj().Name()
+	}
+	return ""
+}
+
+// renderExamples renders every example in exs through renderExample.
+func renderExamples(fset *token.FileSet, exs []*doc.Example) []Example {
+	if len(exs) == 0 {
+		return nil
+	}
+	out := make([]Example, 0, len(exs))
+	for _, ex := range exs {
+		out = append(out, renderExample(fset, ex))
+	}
+	return out
+}
+
+// renderExample formats ex's play-source through format.Node, the same
+// way `go test` and godoc render example code.
+func renderExample(fset *token.FileSet, ex *doc.Example) Example {
+	node := ex.Code
+	if ex.Play != nil {
+		node = ex.Play
+	}
+	var buf bytes.Buffer
+	format.Node(&buf, fset, node)
+	return Example{
+		Name:        ex.Name,
+		Doc:         ex.Doc,
+		Code:        buf.String(),
+		Output:      ex.Output,
+		Unordered:   ex.Unordered,
+		EmptyOutput: ex.EmptyOutput,
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import "go/doc/comment"
+
+// TextFormat controls how doc comments are rendered onto a Doc.
+type TextFormat struct {
+	Width      int    // wrap width in columns, cmd/doc's "punched card" default is 80
+	Prefix     string // prefix applied to every line of text
+	CodePrefix string // prefix applied to indented code blocks
+
+	// Mode is one of "raw", "text" or "markdown". "raw" leaves the doc
+	// comment untouched; "text" reflows it through comment.Printer.Text;
+	// "markdown" additionally renders comment.Printer.Markdown into
+	// Doc.Markdown.
+	Mode string
+}
+
+// render parses raw, a doc comment as returned by ast.CommentGroup.Text,
+// through go/doc/comment and renders it according to f.
+func (f *TextFormat) render(raw string) (text, markdown string) {
+	if f == nil || f.Mode == "raw" || raw == "" {
+		return raw, ""
+	}
+
+	var parser comment.Parser
+	parsed := parser.Parse(raw)
+
+	printer := &comment.Printer{
+		TextWidth:      f.Width,
+		TextPrefix:     f.Prefix,
+		TextCodePrefix: f.CodePrefix,
+	}
+	text = string(printer.Text(parsed))
+	if f.Mode == "markdown" {
+		markdown = string(printer.Markdown(parsed))
+	}
+	return text, markdown
+}
+
+// setText reflows raw through format, stores the result on d, and
+// records whether raw carries a "Deprecated:" marker.
+func (d *Doc) setText(raw string, format *TextFormat) {
+	d.Doc, d.Markdown = format.render(raw)
+	d.Deprecated, d.IsDeprecated = deprecated(raw)
+}
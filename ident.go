@@ -7,6 +7,8 @@ import (
 	"go/printer"
 	"go/token"
 	"go/types"
+	"path/filepath"
+	"strings"
 
 	"golang.org/x/tools/go/loader"
 )
@@ -35,6 +37,7 @@ func findVarSpec(decl *ast.GenDecl, symbol string) *ast.ValueSpec {
 
 func formatNode(n ast.Node, obj types.Object, prog *loader.Program) string {
 	var nc ast.Node
+	stripFunc := false
 	// Render a copy of the node with no documentation. We
 	// emit the documentation ourself.
 	switch n := n.(type) {
@@ -70,15 +73,22 @@ func formatNode(n ast.Node, obj types.Object, prog *loader.Program) string {
 		}
 		nc = &cp
 	case *ast.Field:
-		// Not supported by go/printer
-
-		// TODO(dominikh): Methods in interfaces are syntactically
-		// represented as fields. Using types.Object.String for those
-		// causes them to look different from real functions.
-		// go/printer doesn't include the import paths in names, while
-		// Object.String does. Fix that.
-
-		return obj.String()
+		if ft, ok := n.Type.(*ast.FuncType); ok && len(n.Names) == 1 {
+			// Interface methods are syntactically represented as
+			// fields. Build a synthetic func decl so they print the
+			// same way a real top-level function does, instead of
+			// falling back to types.Object.String, which (unlike
+			// go/printer) qualifies names with full import paths. The
+			// "func " keyword go/printer adds for a FuncDecl is then
+			// stripped below, since interface methods don't have one.
+			nc = &ast.FuncDecl{Name: n.Names[0], Type: ft}
+			stripFunc = true
+		} else {
+			// An embedded interface: print its type expression as
+			// written, which preserves any import alias already
+			// resolved in the source.
+			nc = n.Type
+		}
 	default:
 		return obj.String()
 	}
@@ -89,11 +99,14 @@ func formatNode(n ast.Node, obj types.Object, prog *loader.Program) string {
 	if err != nil {
 		return obj.String()
 	}
+	if stripFunc {
+		return strings.TrimPrefix(buf.String(), "func ")
+	}
 	return buf.String()
 }
 
 // IdentDoc attempts to get the documentation for a *ast.Ident.
-func IdentDoc(id *ast.Ident, info *loader.PackageInfo, prog *loader.Program) (*Doc, error) {
+func IdentDoc(id *ast.Ident, info *loader.PackageInfo, prog *loader.Program, format *TextFormat) (*Doc, error) {
 	// get definition of identifier
 	obj := info.ObjectOf(id)
 	pkgPath := ""
@@ -103,7 +116,7 @@ func IdentDoc(id *ast.Ident, info *loader.PackageInfo, prog *loader.Program) (*D
 
 	// handle packages imported under a different name
 	if p, ok := obj.(*types.PkgName); ok {
-		return PackageDoc(prog.Fset, p.Imported().Path())
+		return PackageDoc(prog.Fset, p.Imported().Path(), format)
 	}
 
 	_, nodes, _ := prog.PathEnclosingInterval(obj.Pos(), obj.Pos())
@@ -129,12 +142,21 @@ func IdentDoc(id *ast.Ident, info *loader.PackageInfo, prog *loader.Program) (*D
 		return nil, fmt.Errorf("No documentation found for %s", obj.Name())
 	}
 
+	dir := filepath.Dir(prog.Fset.Position(obj.Pos()).Filename)
+	if exPkg, err := examplePackage(prog.Fset, pkgPath, dir); err == nil {
+		doc.Examples = renderExamples(prog.Fset, examplesForObj(exPkg, obj))
+	}
+
+	if tn, ok := obj.(*types.TypeName); ok {
+		doc.Methods, doc.Funcs, doc.Consts, doc.Vars = typeMembers(tn, prog)
+	}
+
 	for _, node := range nodes {
 		//fmt.Printf("for %s: found %T\n%#v\n", id.Name, node, node)
 		switch n := node.(type) {
 		case *ast.FuncDecl:
 			// TODO "relative-to" output format...
-			doc.Doc = n.Doc.Text()
+			doc.setText(n.Doc.Text(), format)
 			return doc, nil
 		case *ast.GenDecl:
 			var constValue string
@@ -153,19 +175,20 @@ func IdentDoc(id *ast.Ident, info *loader.PackageInfo, prog *loader.Program) (*D
 				}
 			}
 			if n.Doc != nil {
-				doc.Doc = n.Doc.Text()
+				text := n.Doc.Text()
 				if constValue != "" {
-					doc.Doc += fmt.Sprintf("\nConstant Value: %s", constValue)
+					text += fmt.Sprintf("\nConstant Value: %s", constValue)
 				}
+				doc.setText(text, format)
 				return doc, nil
 			}
 		case *ast.Field:
 			// check the doc first, if not present, then look for a comment
 			if n.Doc != nil {
-				doc.Doc = n.Doc.Text()
+				doc.setText(n.Doc.Text(), format)
 				return doc, nil
 			} else if n.Comment != nil {
-				doc.Doc = n.Comment.Text()
+				doc.setText(n.Comment.Text(), format)
 				return doc, nil
 			}
 		}
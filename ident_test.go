@@ -0,0 +1,108 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"golang.org/x/tools/go/loader"
+)
+
+const ifaceSrc = `package p
+
+import (
+	"io"
+	ioalias "io"
+)
+
+type Reader interface {
+	io.Reader
+	ReadFrom(r ioalias.Reader) (n int64, err error)
+}
+`
+
+func TestFormatNodeInterfaceField(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", ifaceSrc, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var conf loader.Config
+	conf.Fset = fset
+	conf.CreatePkgs = []loader.PkgSpec{{Path: "p", Files: []*ast.File{f}}}
+	prog, err := conf.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := prog.Created[0]
+	iface := findInterfaceType(f, "Reader")
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		// The embedded io.Reader should print exactly as written,
+		// not as the fully-qualified name types.Object.String would
+		// produce.
+		{"io.Reader", "io.Reader"},
+		// ReadFrom's parameter uses an aliased import; go/printer
+		// must preserve the alias used in the source.
+		{"ReadFrom", "ReadFrom(r ioalias.Reader) (n int64, err error)"},
+	}
+
+	for _, tt := range tests {
+		field := findField(iface, tt.name)
+		if field == nil {
+			t.Fatalf("field %s not found", tt.name)
+		}
+
+		var obj types.Object
+		if len(field.Names) == 1 {
+			obj = info.ObjectOf(field.Names[0])
+		} else {
+			obj = info.ObjectOf(field.Type.(*ast.SelectorExpr).Sel)
+		}
+
+		if got := formatNode(field, obj, prog); got != tt.want {
+			t.Errorf("formatNode(%s) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func findInterfaceType(f *ast.File, name string) *ast.InterfaceType {
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok || ts.Name.Name != name {
+				continue
+			}
+			if it, ok := ts.Type.(*ast.InterfaceType); ok {
+				return it
+			}
+		}
+	}
+	return nil
+}
+
+func findField(it *ast.InterfaceType, name string) *ast.Field {
+	for _, field := range it.Methods.List {
+		if len(field.Names) == 1 && field.Names[0].Name == name {
+			return field
+		}
+		if len(field.Names) == 0 {
+			if sel, ok := field.Type.(*ast.SelectorExpr); ok {
+				if x, ok := sel.X.(*ast.Ident); ok && x.Name+"."+sel.Sel.Name == name {
+					return field
+				}
+			}
+		}
+	}
+	return nil
+}
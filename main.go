@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/loader"
+)
+
+var (
+	posFlag            = flag.String("pos", "", "Query position, in the form 'file.go:#offset'")
+	jsonFlag           = flag.Bool("json", false, "Emit output as JSON")
+	lineLength         = flag.Int("linelength", 80, "Wrap doc comments at this column (cmd/doc's \"punched card\" width)")
+	prefixFlag         = flag.String("prefix", "", "Prefix applied to every line of a doc comment")
+	formatFlag         = flag.String("doc-format", "text", "Doc comment rendering: raw, text, or markdown")
+	notesFlag          = flag.Bool("notes", false, "List BUG/TODO/FIXME notes for the package in the given directory (or the current one)")
+	deprecatedOnlyFlag = flag.Bool("deprecated-only", false, "List every deprecated export in the package in the given directory (or the current one)")
+)
+
+func main() {
+	flag.Parse()
+
+	format := &TextFormat{Width: *lineLength, Prefix: *prefixFlag, Mode: *formatFlag}
+
+	if *notesFlag {
+		dir := "."
+		if flag.NArg() > 0 {
+			dir = flag.Arg(0)
+		}
+		d, err := NotesDoc(token.NewFileSet(), dir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		printDoc(d)
+		return
+	}
+
+	if *deprecatedOnlyFlag {
+		dir := "."
+		if flag.NArg() > 0 {
+			dir = flag.Arg(0)
+		}
+		docs, err := DeprecatedExports(token.NewFileSet(), dir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		for _, d := range docs {
+			printDoc(d)
+		}
+		return
+	}
+
+	file, offset, err := parsePos(*posFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	d, err := Run(file, offset, format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	printDoc(d)
+}
+
+// printDoc writes d to stdout, as JSON when -json was given, otherwise
+// as plain text.
+func printDoc(d *Doc) {
+	if *jsonFlag {
+		json.NewEncoder(os.Stdout).Encode(d)
+		return
+	}
+	fmt.Println(d.Title)
+	if d.IsDeprecated {
+		fmt.Printf("Deprecated: %s\n", d.Deprecated)
+	}
+	if d.Doc != "" {
+		fmt.Println()
+		fmt.Println(d.Doc)
+	}
+	for marker, notes := range d.Notes {
+		for _, n := range notes {
+			fmt.Printf("%s(%s): %s: %s\n", marker, n.UID, n.Pos, n.Body)
+		}
+	}
+}
+
+// parsePos splits a "-pos" flag value of the form "file.go:#offset" into
+// its filename and byte offset.
+func parsePos(pos string) (file string, offset int, err error) {
+	i := strings.LastIndex(pos, ":#")
+	if i < 0 {
+		return "", 0, fmt.Errorf("invalid -pos value %q, expected 'file.go:#offset'", pos)
+	}
+	file = pos[:i]
+	offset, err = strconv.Atoi(pos[i+2:])
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid offset in -pos value %q: %v", pos, err)
+	}
+	return file, offset, nil
+}
+
+// Run loads the package containing file and returns documentation for the
+// identifier found at offset.
+func Run(file string, offset int, format *TextFormat) (*Doc, error) {
+	files, err := packageFiles(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var conf loader.Config
+	conf.ParserMode = parser.ParseComments
+	conf.CreateFromFilenames("", files...)
+
+	prog, err := conf.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	id, info, err := identAt(prog, file, offset)
+	if err != nil {
+		return nil, err
+	}
+	return IdentDoc(id, info, prog, format)
+}
+
+// packageFiles returns the .go files that sit alongside file and declare
+// the same package, i.e. the rest of its package. A directory's external
+// test package (package foo_test, as opposed to foo) is deliberately
+// excluded: loader.Config.CreateFromFilenames requires every file it's
+// given to declare the same package, so mixing the two would make the
+// primary, type-checked load of every identifier in the directory fail.
+// Examples from _test.go files, internal or external, are still found -
+// via the separate, AST-only path in examplePackage.
+func packageFiles(file string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(filepath.Dir(file), "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	target, err := packageClause(fset, file)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, m := range matches {
+		if name, err := packageClause(fset, m); err == nil && name == target {
+			files = append(files, m)
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files found next to %s", file)
+	}
+	return files, nil
+}
+
+// packageClause returns the package name declared by the Go source file
+// at path.
+func packageClause(fset *token.FileSet, path string) (string, error) {
+	f, err := parser.ParseFile(fset, path, nil, parser.PackageClauseOnly)
+	if err != nil {
+		return "", err
+	}
+	return f.Name.Name, nil
+}
+
+// identAt returns the *ast.Ident at offset within file, along with the
+// package it belongs to.
+func identAt(prog *loader.Program, file string, offset int) (*ast.Ident, *loader.PackageInfo, error) {
+	for _, info := range prog.InitialPackages() {
+		for _, f := range info.Files {
+			tf := prog.Fset.File(f.Pos())
+			if tf == nil || tf.Name() != file {
+				continue
+			}
+			if offset < 0 || offset > tf.Size() {
+				return nil, nil, fmt.Errorf("offset %d is out of range for %s", offset, file)
+			}
+			pos := tf.Pos(offset)
+			var found *ast.Ident
+			ast.Inspect(f, func(n ast.Node) bool {
+				if id, ok := n.(*ast.Ident); ok && id.Pos() <= pos && pos <= id.End() {
+					found = id
+				}
+				return true
+			})
+			if found == nil {
+				return nil, nil, fmt.Errorf("no identifier at %s:#%d", file, offset)
+			}
+			return found, info, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("file %s not found in loaded program", file)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"go/doc"
+	"go/token"
+)
+
+// Note is a BUG(who), TODO(who) or FIXME(who) marker found in a doc
+// comment, as produced by go/doc.Package.Notes.
+type Note struct {
+	UID  string         `json:"uid"`
+	Body string         `json:"body"`
+	Pos  token.Position `json:"pos"`
+}
+
+// notesFromPackage converts pkg.Notes, keyed by marker (BUG, TODO,
+// FIXME, ...), into our own representation.
+func notesFromPackage(fset *token.FileSet, pkg *doc.Package) map[string][]Note {
+	if len(pkg.Notes) == 0 {
+		return nil
+	}
+	notes := make(map[string][]Note, len(pkg.Notes))
+	for marker, group := range pkg.Notes {
+		for _, n := range group {
+			notes[marker] = append(notes[marker], Note{
+				UID:  n.UID,
+				Body: n.Body,
+				Pos:  fset.Position(n.Pos),
+			})
+		}
+	}
+	return notes
+}
+
+// NotesDoc scans the package in dir for BUG/TODO/FIXME style notes and
+// returns them on a package-level Doc, for the "-notes" CLI mode.
+func NotesDoc(fset *token.FileSet, dir string) (*Doc, error) {
+	files, err := parseDir(fset, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg, err := doc.NewFromFiles(fset, files, ".")
+	if err != nil {
+		return nil, err
+	}
+	return &Doc{
+		Name:  pkg.Name,
+		Title: fmt.Sprintf("package %s", pkg.Name),
+		Notes: notesFromPackage(fset, pkg),
+	}, nil
+}
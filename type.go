@@ -0,0 +1,124 @@
+package main
+
+import (
+	"go/ast"
+	"go/doc"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/loader"
+)
+
+// typeMembers gathers the method set of tn (including promoted methods,
+// via both T and *T), the package-level functions that construct it, and
+// the package-level consts and vars declared with it. This mirrors the
+// Methods/Funcs/Consts/Vars fields of go/doc.Type.
+func typeMembers(tn *types.TypeName, prog *loader.Program) (methods, funcs, consts, vars []*Doc) {
+	named, ok := tn.Type().(*types.Named)
+	if !ok {
+		return nil, nil, nil, nil
+	}
+
+	seen := map[string]bool{}
+	for _, t := range [...]types.Type{named, types.NewPointer(named)} {
+		ms := types.NewMethodSet(t)
+		for i := 0; i < ms.Len(); i++ {
+			fn, ok := ms.At(i).Obj().(*types.Func)
+			if !ok || seen[fn.Name()] {
+				continue
+			}
+			seen[fn.Name()] = true
+			methods = append(methods, briefDoc(fn, prog))
+		}
+	}
+
+	if tn.Pkg() != nil {
+		scope := tn.Pkg().Scope()
+		for _, name := range scope.Names() {
+			switch o := scope.Lookup(name).(type) {
+			case *types.Func:
+				if isConstructorFor(o, named) {
+					funcs = append(funcs, briefDoc(o, prog))
+				}
+			case *types.Const:
+				if isValueOfType(o.Type(), named) {
+					consts = append(consts, briefDoc(o, prog))
+				}
+			case *types.Var:
+				if isValueOfType(o.Type(), named) {
+					vars = append(vars, briefDoc(o, prog))
+				}
+			}
+		}
+	}
+
+	byName := func(d []*Doc) {
+		sort.Slice(d, func(i, j int) bool { return d[i].Name < d[j].Name })
+	}
+	byName(methods)
+	byName(funcs)
+	byName(consts)
+	byName(vars)
+	return methods, funcs, consts, vars
+}
+
+// isConstructorFor reports whether fn's first result is named or *named,
+// the same convention cmd/doc uses to associate constructors with types.
+func isConstructorFor(fn *types.Func, named *types.Named) bool {
+	sig := fn.Type().(*types.Signature)
+	if sig.Recv() != nil || sig.Results().Len() == 0 {
+		return false
+	}
+	return isValueOfType(sig.Results().At(0).Type(), named)
+}
+
+// isValueOfType reports whether t is named or a pointer to named.
+func isValueOfType(t types.Type, named *types.Named) bool {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	return types.Identical(t, named)
+}
+
+// briefDoc renders obj's signature and the first sentence of its doc
+// comment, for use in a type's Methods/Funcs/Consts/Vars.
+func briefDoc(obj types.Object, prog *loader.Program) *Doc {
+	d := &Doc{Name: obj.Name()}
+	if obj.Pkg() != nil {
+		d.Import = obj.Pkg().Path()
+	}
+
+	_, nodes, _ := prog.PathEnclosingInterval(obj.Pos(), obj.Pos())
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			d.Title = formatNode(n, obj, prog)
+			raw := n.Doc.Text()
+			d.Doc = doc.Synopsis(raw)
+			d.Deprecated, d.IsDeprecated = deprecated(raw)
+			return d
+		case *ast.Field:
+			d.Title = formatNode(n, obj, prog)
+			cg := n.Doc
+			if cg == nil {
+				cg = n.Comment
+			}
+			if cg != nil {
+				raw := cg.Text()
+				d.Doc = doc.Synopsis(raw)
+				d.Deprecated, d.IsDeprecated = deprecated(raw)
+			}
+			return d
+		case *ast.GenDecl:
+			d.Title = formatNode(n, obj, prog)
+			if n.Doc != nil {
+				raw := n.Doc.Text()
+				d.Doc = doc.Synopsis(raw)
+				d.Deprecated, d.IsDeprecated = deprecated(raw)
+			}
+			return d
+		}
+	}
+	d.Title = obj.String()
+	return d
+}